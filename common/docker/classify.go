@@ -0,0 +1,25 @@
+package docker
+
+import (
+	docker "github.com/fsouza/go-dockerclient"
+
+	"github.com/weaveworks/weave/common/docker/errdefs"
+)
+
+// classify turns the concrete error types go-dockerclient returns into
+// errdefs-classified errors, so callers can use errdefs.IsNotFound and
+// friends instead of type-asserting against the client library or scraping
+// error strings.
+func classify(err error) error {
+	if err == docker.ErrConnectionRefused {
+		return errdefs.Unavailable(err)
+	}
+	switch err.(type) {
+	case *docker.NoSuchContainer, *docker.NoSuchNetwork, *docker.NoSuchNetworkOrEndpoint:
+		return errdefs.NotFound(err)
+	case *docker.ContainerAlreadyRunning, *docker.ContainerNotRunning:
+		return errdefs.Conflict(err)
+	default:
+		return err
+	}
+}
@@ -1,7 +1,6 @@
 package docker
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +8,7 @@ import (
 	docker "github.com/fsouza/go-dockerclient"
 
 	. "github.com/weaveworks/weave/common"
+	"github.com/weaveworks/weave/common/docker/errdefs"
 )
 
 const (
@@ -16,12 +16,6 @@ const (
 	MaxInterval     = 20 * time.Second
 )
 
-// An observer for container events
-type ContainerObserver interface {
-	ContainerStarted(ident string)
-	ContainerDied(ident string)
-}
-
 type Client struct {
 	*docker.Client
 }
@@ -76,75 +70,26 @@ func (c *Client) Info() string {
 	}
 }
 
-// AddObserver adds an observer for docker events
-func (c *Client) AddObserver(ob ContainerObserver) error {
-	go func() {
-		retryInterval := InitialInterval
-		for {
-			events := make(chan *docker.APIEvents)
-			if err := c.AddEventListener(events); err != nil {
-				Log.Errorf("[docker] Unable to add listener to Docker API: %s - retrying in %ds", err, retryInterval/time.Second)
-			} else {
-				start := time.Now()
-				for event := range events {
-					switch event.Status {
-					case "start":
-						id := event.ID
-						ob.ContainerStarted(id)
-					case "die":
-						id := event.ID
-						ob.ContainerDied(id)
-					}
-				}
-				if time.Since(start) > retryInterval {
-					retryInterval = InitialInterval
-				}
-				Log.Errorf("[docker] Event listener channel closed - retrying subscription in %ds", retryInterval/time.Second)
-			}
-			time.Sleep(retryInterval)
-			retryInterval = retryInterval * 3 / 2
-			if retryInterval > MaxInterval {
-				retryInterval = MaxInterval
-			}
-		}
-	}()
-	return nil
-}
-
 // IsContainerNotRunning returns true if we have checked with Docker that the ID is not running
 func (c *Client) IsContainerNotRunning(idStr string) bool {
 	container, err := c.InspectContainer(idStr)
 	if err == nil {
 		return !container.State.Running || container.State.Restarting
 	}
-	if _, notThere := err.(*docker.NoSuchContainer); notThere {
+	if errdefs.IsNotFound(err) {
 		return true
 	}
 	Log.Errorf("[docker] Could not check container status: %s", err)
 	return false
 }
 
-// This is intended to find an IP address that we can reach the container on;
-// if it is on the Docker bridge network then that address; if on the host network
-// then localhost
-func (c *Client) GetContainerIP(nameOrID string) (string, error) {
-	Log.Debugf("Getting IP for container %s", nameOrID)
-	info, err := c.InspectContainer(nameOrID)
+// InspectContainer wraps the embedded client's InspectContainer so that
+// callers can classify the error with errdefs.IsNotFound and friends instead
+// of type-asserting against go-dockerclient's concrete error types.
+func (c *Client) InspectContainer(idStr string) (*docker.Container, error) {
+	container, err := c.Client.InspectContainer(idStr)
 	if err != nil {
-		return "", err
-	}
-	if info.NetworkSettings.Networks != nil {
-		Log.Debugln("Networks: ", info.NetworkSettings.Networks)
-		if bridgeNetwork, ok := info.NetworkSettings.Networks["bridge"]; ok {
-			return bridgeNetwork.IPAddress, nil
-		} else if _, ok := info.NetworkSettings.Networks["host"]; ok {
-			return "127.0.0.1", nil
-		}
-	} else if info.HostConfig.NetworkMode == "host" {
-		return "127.0.0.1", nil
-	}
-	if info.NetworkSettings.IPAddress == "" {
-		return "", errors.New("No IP address found for container " + nameOrID)
+		return nil, classify(err)
 	}
-	return info.NetworkSettings.IPAddress, nil
+	return container, nil
 }
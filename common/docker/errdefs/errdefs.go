@@ -0,0 +1,89 @@
+// Package errdefs defines a small set of interfaces for the error
+// conditions callers actually need to distinguish when talking to Docker,
+// following the same pattern as moby's errdefs package. Rather than matching
+// on concrete types or scraping error strings, code that wraps a Docker
+// error implements one of these interfaces, and callers classify it with
+// IsNotFound, IsConflict, IsUnavailable or IsUnauthorized.
+package errdefs
+
+// ErrNotFound signals that the requested object (container, network,
+// image, ...) does not exist.
+type ErrNotFound interface {
+	NotFound() bool
+}
+
+// ErrConflict signals that the request could not be completed because of a
+// conflict with the current state of the object, e.g. trying to remove a
+// running container.
+type ErrConflict interface {
+	Conflict() bool
+}
+
+// ErrUnavailable signals that the Docker daemon could not be reached, or
+// could not service the request because it is shutting down or overloaded.
+type ErrUnavailable interface {
+	Unavailable() bool
+}
+
+// ErrUnauthorized signals that the caller is not authorized to perform the
+// requested operation.
+type ErrUnauthorized interface {
+	Unauthorized() bool
+}
+
+type causer interface {
+	Cause() error
+}
+
+// unwrap walks the Cause() chain of err, calling match at each step until it
+// returns true, mirroring the way pkg/errors-wrapped errors are inspected
+// elsewhere in weave.
+func unwrap(err error, match func(error) bool) bool {
+	for err != nil {
+		if match(err) {
+			return true
+		}
+		c, ok := err.(causer)
+		if !ok {
+			return false
+		}
+		err = c.Cause()
+	}
+	return false
+}
+
+// IsNotFound returns true if err, or any error in its Cause() chain,
+// implements ErrNotFound and reports true.
+func IsNotFound(err error) bool {
+	return unwrap(err, func(err error) bool {
+		e, ok := err.(ErrNotFound)
+		return ok && e.NotFound()
+	})
+}
+
+// IsConflict returns true if err, or any error in its Cause() chain,
+// implements ErrConflict and reports true.
+func IsConflict(err error) bool {
+	return unwrap(err, func(err error) bool {
+		e, ok := err.(ErrConflict)
+		return ok && e.Conflict()
+	})
+}
+
+// IsUnavailable returns true if err, or any error in its Cause() chain,
+// implements ErrUnavailable and reports true.
+func IsUnavailable(err error) bool {
+	return unwrap(err, func(err error) bool {
+		e, ok := err.(ErrUnavailable)
+		return ok && e.Unavailable()
+	})
+}
+
+// IsUnauthorized returns true if err, or any error in its Cause() chain,
+// implements ErrUnauthorized and reports true.
+func IsUnauthorized(err error) bool {
+	return unwrap(err, func(err error) bool {
+		e, ok := err.(ErrUnauthorized)
+		return ok && e.Unauthorized()
+	})
+}
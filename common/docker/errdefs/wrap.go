@@ -0,0 +1,47 @@
+package errdefs
+
+// wrapped attaches a classification to an underlying error while preserving
+// it for unwrapping and message formatting.
+type wrapped struct {
+	error
+	class string
+}
+
+func (w *wrapped) Cause() error { return w.error }
+
+func (w *wrapped) NotFound() bool     { return w.class == "not_found" }
+func (w *wrapped) Conflict() bool     { return w.class == "conflict" }
+func (w *wrapped) Unavailable() bool  { return w.class == "unavailable" }
+func (w *wrapped) Unauthorized() bool { return w.class == "unauthorized" }
+
+// NotFound wraps err so that IsNotFound(err) reports true.
+func NotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrapped{err, "not_found"}
+}
+
+// Conflict wraps err so that IsConflict(err) reports true.
+func Conflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrapped{err, "conflict"}
+}
+
+// Unavailable wraps err so that IsUnavailable(err) reports true.
+func Unavailable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrapped{err, "unavailable"}
+}
+
+// Unauthorized wraps err so that IsUnauthorized(err) reports true.
+func Unauthorized(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrapped{err, "unauthorized"}
+}
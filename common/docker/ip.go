@@ -0,0 +1,210 @@
+package docker
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	docker "github.com/fsouza/go-dockerclient"
+
+	. "github.com/weaveworks/weave/common"
+)
+
+// Scope describes how far an address on a container network reaches.
+type Scope string
+
+const (
+	ScopeBridge  Scope = "bridge"
+	ScopeHost    Scope = "host"
+	ScopeOverlay Scope = "overlay"
+)
+
+// ContainerAddress is one IP address a container can be reached on, together
+// with the network it belongs to and that network's scope.
+type ContainerAddress struct {
+	IP      string
+	Network string
+	Scope   Scope
+}
+
+// IPOption configures GetContainerIP and ListContainerIPs.
+type IPOption func(*ipConfig)
+
+type ipConfig struct {
+	preferNetwork        string
+	followContainerMode  bool
+	requireReachableFrom net.IP
+}
+
+// PreferNetwork returns the address on the named network first, ahead of any
+// other network the container is attached to.
+func PreferNetwork(name string) IPOption {
+	return func(c *ipConfig) { c.preferNetwork = name }
+}
+
+// AllowNetworkMode, when mode is "container", makes GetContainerIP follow a
+// container started with `--network container:<id>` through to the
+// container whose network namespace it shares, instead of reporting no
+// address for it.
+func AllowNetworkMode(mode string) IPOption {
+	return func(c *ipConfig) {
+		if mode == "container" {
+			c.followContainerMode = true
+		}
+	}
+}
+
+// RequireReachableFrom restricts results to networks whose subnet, as
+// reported by Docker, contains hostIP - so callers don't get back an
+// address nothing on this host can actually route to. Networks Docker
+// doesn't report a subnet for are kept, on the assumption they're
+// reachable.
+func RequireReachableFrom(hostIP string) IPOption {
+	return func(c *ipConfig) { c.requireReachableFrom = net.ParseIP(hostIP) }
+}
+
+// GetContainerIP finds an address to reach nameOrID on. With no options it
+// prefers the "bridge" network, then "host", then any other network in
+// alphabetical order; the legacy NetworkSettings.IPAddress is used only when
+// none of those gives an address.
+func (c *Client) GetContainerIP(nameOrID string, opts ...IPOption) (ContainerAddress, error) {
+	addrs, err := c.ListContainerIPs(nameOrID, opts...)
+	if err != nil {
+		return ContainerAddress{}, err
+	}
+	if len(addrs) == 0 {
+		return ContainerAddress{}, fmt.Errorf("no IP address found for container %s", nameOrID)
+	}
+	return addrs[0], nil
+}
+
+// ListContainerIPs returns every address nameOrID can be reached on - for
+// diagnostics, or for callers that want to choose among several
+// attachments themselves.
+func (c *Client) ListContainerIPs(nameOrID string, opts ...IPOption) ([]ContainerAddress, error) {
+	cfg := &ipConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return c.listContainerIPs(nameOrID, cfg, map[string]bool{})
+}
+
+func (c *Client) listContainerIPs(nameOrID string, cfg *ipConfig, visited map[string]bool) ([]ContainerAddress, error) {
+	if visited[nameOrID] {
+		return nil, fmt.Errorf("cyclic container:<id> network mode involving %s", nameOrID)
+	}
+	visited[nameOrID] = true
+
+	Log.Debugf("Getting IP for container %s", nameOrID)
+	info, err := c.InspectContainer(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.followContainerMode && strings.HasPrefix(string(info.HostConfig.NetworkMode), "container:") {
+		target := strings.TrimPrefix(string(info.HostConfig.NetworkMode), "container:")
+		return c.listContainerIPs(target, cfg, visited)
+	}
+
+	return resolveAddresses(info, cfg), nil
+}
+
+// resolveAddresses applies cfg to a single container's NetworkSettings,
+// independently of how info was fetched. Kept separate from
+// listContainerIPs so the network-mode handling can be tested without a
+// Docker daemon.
+func resolveAddresses(info *docker.Container, cfg *ipConfig) []ContainerAddress {
+	var addrs []ContainerAddress
+	if info.NetworkSettings.Networks != nil {
+		Log.Debugln("Networks: ", info.NetworkSettings.Networks)
+		for _, name := range networkOrder(info.NetworkSettings.Networks, cfg.preferNetwork) {
+			if name == "host" {
+				// Docker reports a "host" entry with no IPAddress for
+				// containers run with --network host.
+				addrs = append(addrs, ContainerAddress{IP: "127.0.0.1", Network: "host", Scope: ScopeHost})
+				continue
+			}
+			if network := info.NetworkSettings.Networks[name]; network.IPAddress != "" {
+				addrs = append(addrs, ContainerAddress{IP: network.IPAddress, Network: name, Scope: scopeOf(name)})
+			}
+		}
+	} else if info.HostConfig.NetworkMode == "host" {
+		addrs = append(addrs, ContainerAddress{IP: "127.0.0.1", Network: "host", Scope: ScopeHost})
+	}
+
+	if len(addrs) == 0 && info.NetworkSettings.IPAddress != "" {
+		addrs = append(addrs, ContainerAddress{IP: info.NetworkSettings.IPAddress, Network: "bridge", Scope: ScopeBridge})
+	}
+
+	if cfg.requireReachableFrom != nil {
+		addrs = filterReachable(addrs, info, cfg.requireReachableFrom)
+	}
+
+	return addrs
+}
+
+// networkOrder returns the names of networks in the order candidates should
+// be considered: an explicitly preferred network first, then the
+// well-known "bridge" and "host" networks - matching GetContainerIP's
+// historical default of preferring bridge, then host - then everything
+// else in a deterministic (alphabetical, rather than Go's randomized map
+// iteration) order.
+func networkOrder(networks map[string]*docker.EndpointSettings, preferred string) []string {
+	var rest []string
+	for name := range networks {
+		if name == preferred || name == "bridge" || name == "host" {
+			continue
+		}
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+
+	var order []string
+	if preferred != "" {
+		if _, ok := networks[preferred]; ok {
+			order = append(order, preferred)
+		}
+	}
+	for _, name := range []string{"bridge", "host"} {
+		if name == preferred {
+			continue
+		}
+		if _, ok := networks[name]; ok {
+			order = append(order, name)
+		}
+	}
+	return append(order, rest...)
+}
+
+// scopeOf classifies a network by its well-known name. Docker doesn't
+// report a network's driver in per-container NetworkSettings, so
+// user-defined bridge networks and swarm overlays are both treated as
+// ScopeOverlay; callers that need to tell them apart should inspect the
+// network itself.
+func scopeOf(network string) Scope {
+	switch network {
+	case "bridge":
+		return ScopeBridge
+	case "host":
+		return ScopeHost
+	default:
+		return ScopeOverlay
+	}
+}
+
+func filterReachable(addrs []ContainerAddress, info *docker.Container, hostIP net.IP) []ContainerAddress {
+	var out []ContainerAddress
+	for _, addr := range addrs {
+		network, ok := info.NetworkSettings.Networks[addr.Network]
+		if !ok || network.IPPrefixLen == 0 {
+			out = append(out, addr)
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", network.IPAddress, network.IPPrefixLen))
+		if err != nil || subnet.Contains(hostIP) {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+func TestResolveAddresses(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		info *docker.Container
+		cfg  ipConfig
+		want []ContainerAddress
+	}{
+		{
+			name: "bridge and user-defined network, no preference, prefers bridge",
+			info: &docker.Container{
+				NetworkSettings: &docker.NetworkSettings{
+					Networks: map[string]*docker.EndpointSettings{
+						"bridge": {IPAddress: "172.17.0.2"},
+						"app":    {IPAddress: "10.0.0.5"},
+					},
+				},
+				HostConfig: &docker.HostConfig{},
+			},
+			want: []ContainerAddress{
+				{IP: "172.17.0.2", Network: "bridge", Scope: ScopeBridge},
+				{IP: "10.0.0.5", Network: "app", Scope: ScopeOverlay},
+			},
+		},
+		{
+			name: "host network reports no IPAddress but must resolve to localhost",
+			info: &docker.Container{
+				NetworkSettings: &docker.NetworkSettings{
+					Networks: map[string]*docker.EndpointSettings{
+						"host": {IPAddress: ""},
+					},
+				},
+				HostConfig: &docker.HostConfig{NetworkMode: "host"},
+			},
+			want: []ContainerAddress{
+				{IP: "127.0.0.1", Network: "host", Scope: ScopeHost},
+			},
+		},
+		{
+			name: "legacy NetworkMode host with no Networks map",
+			info: &docker.Container{
+				NetworkSettings: &docker.NetworkSettings{},
+				HostConfig:      &docker.HostConfig{NetworkMode: "host"},
+			},
+			want: []ContainerAddress{
+				{IP: "127.0.0.1", Network: "host", Scope: ScopeHost},
+			},
+		},
+		{
+			name: "PreferNetwork takes priority over bridge",
+			info: &docker.Container{
+				NetworkSettings: &docker.NetworkSettings{
+					Networks: map[string]*docker.EndpointSettings{
+						"bridge": {IPAddress: "172.17.0.2"},
+						"weave":  {IPAddress: "10.32.0.3"},
+					},
+				},
+				HostConfig: &docker.HostConfig{},
+			},
+			cfg: ipConfig{preferNetwork: "weave"},
+			want: []ContainerAddress{
+				{IP: "10.32.0.3", Network: "weave", Scope: ScopeOverlay},
+				{IP: "172.17.0.2", Network: "bridge", Scope: ScopeBridge},
+			},
+		},
+		{
+			name: "legacy IPAddress fallback when Networks is nil",
+			info: &docker.Container{
+				NetworkSettings: &docker.NetworkSettings{IPAddress: "172.17.0.9"},
+				HostConfig:      &docker.HostConfig{},
+			},
+			want: []ContainerAddress{
+				{IP: "172.17.0.9", Network: "bridge", Scope: ScopeBridge},
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := resolveAddresses(testCase.info, &testCase.cfg)
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("resolveAddresses() = %+v, want %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNetworkOrder(t *testing.T) {
+	networks := map[string]*docker.EndpointSettings{
+		"bridge": {},
+		"host":   {},
+		"zeta":   {},
+		"alpha":  {},
+	}
+
+	got := networkOrder(networks, "")
+	want := []string{"bridge", "host", "alpha", "zeta"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("networkOrder() = %v, want %v", got, want)
+	}
+
+	got = networkOrder(networks, "zeta")
+	want = []string{"zeta", "bridge", "host", "alpha"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("networkOrder() with preference = %v, want %v", got, want)
+	}
+}
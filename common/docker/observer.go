@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+
+	. "github.com/weaveworks/weave/common"
+	"github.com/weaveworks/weave/common/docker/errdefs"
+)
+
+// An observer for container events
+type ContainerObserver interface {
+	ContainerStarted(ident string)
+	ContainerDied(ident string)
+}
+
+// ContainerObserverExt is an optional extension of ContainerObserver for
+// callers that want to react to the wider range of events Docker emits for
+// a container - network attach/detach, health checks, pause/unpause, rename
+// and removal - rather than just start and die. AddObserver type-asserts
+// for this interface, so adding it is backward compatible with existing
+// ContainerObserver implementations.
+type ContainerObserverExt interface {
+	ContainerObserver
+
+	// ContainerEvent is called for "start" and "die" and for the event kinds
+	// listed above; other events AddObserver receives are not passed on. It
+	// gets the full event payload so callers can inspect actor attributes
+	// such as the network name a container was connected to.
+	ContainerEvent(ev *docker.APIEvents)
+}
+
+// ObserverOption configures AddObserver.
+type ObserverOption func(*observerConfig)
+
+type observerConfig struct {
+	filters map[string][]string
+}
+
+// ObserverFilters restricts the Docker events AddObserver subscribes to, so
+// weave doesn't waste cycles decoding events it has no interest in, e.g.
+// image pulls or volume events. See the Docker API documentation for the
+// supported filter keys ("event", "type", "label", ...).
+func ObserverFilters(filters map[string][]string) ObserverOption {
+	return func(c *observerConfig) { c.filters = filters }
+}
+
+// AddObserver adds an observer for docker events. Before returning, it
+// enumerates the containers already running and synthesizes a
+// ContainerStarted callback for each of them, so observers see a consistent
+// view regardless of when they attached. If the event stream is
+// interrupted, AddObserver reconnects and resumes from the last event it
+// processed instead of silently missing whatever happened while
+// disconnected.
+func (c *Client) AddObserver(ob ContainerObserver, opts ...ObserverOption) error {
+	cfg := &observerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	ext, hasExt := ob.(ContainerObserverExt)
+
+	// Capture the time before listing, so that the first Events
+	// subscription below replays anything that started in the gap between
+	// this snapshot and that subscription taking effect.
+	snapshotTime := time.Now().Unix()
+	containers, err := c.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		ob.ContainerStarted(container.ID)
+	}
+
+	go func() {
+		retryInterval := InitialInterval
+		lastEventTime := snapshotTime
+		for {
+			events, err := c.Events(eventsOptions(cfg.filters, lastEventTime))
+			if err != nil {
+				err = classify(err)
+				if errdefs.IsUnavailable(err) {
+					Log.Errorf("[docker] Docker API unavailable: %s - retrying in %ds", err, retryInterval/time.Second)
+				} else {
+					Log.Errorf("[docker] Unable to add listener to Docker API: %s - retrying in %ds", err, retryInterval/time.Second)
+				}
+			} else {
+				start := time.Now()
+				for event := range events {
+					lastEventTime = event.Time
+					switch event.Status {
+					case "start":
+						ob.ContainerStarted(event.ID)
+					case "die":
+						ob.ContainerDied(event.ID)
+					}
+					if hasExt && isInterestingExtEvent(event) {
+						ext.ContainerEvent(event)
+					}
+				}
+				if time.Since(start) > retryInterval {
+					retryInterval = InitialInterval
+				}
+				Log.Errorf("[docker] Event listener channel closed - retrying subscription in %ds", retryInterval/time.Second)
+			}
+			time.Sleep(retryInterval)
+			retryInterval = retryInterval * 3 / 2
+			if retryInterval > MaxInterval {
+				retryInterval = MaxInterval
+			}
+		}
+	}()
+	return nil
+}
+
+// eventsOptions builds the options for the next Events subscription,
+// replaying anything that happened at or after lastEventTime - the
+// pre-listing snapshot time on the first call, or the time of the last
+// event actually processed on every reconnect after that - so a dropped
+// connection never silently skips events.
+func eventsOptions(filters map[string][]string, lastEventTime int64) docker.EventsOptions {
+	return docker.EventsOptions{Filters: filters, Since: strconv.FormatInt(lastEventTime, 10)}
+}
+
+// isInterestingExtEvent reports whether ev is one of the event kinds
+// ContainerObserverExt cares about: the "start"/"die" pair every
+// ContainerObserver already sees, plus the extended kinds.
+func isInterestingExtEvent(ev *docker.APIEvents) bool {
+	switch {
+	case ev.Status == "start" || ev.Status == "die":
+		return true
+	case ev.Type == "network" && (ev.Action == "connect" || ev.Action == "disconnect"):
+		return true
+	case ev.Type == "container" &&
+		(strings.HasPrefix(ev.Action, "health_status") ||
+			ev.Action == "pause" || ev.Action == "unpause" ||
+			ev.Action == "rename" || ev.Action == "destroy"):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+func TestEventsOptionsSeedsSinceFromLastEventTime(t *testing.T) {
+	options := eventsOptions(nil, 1234)
+	if options.Since != "1234" {
+		t.Errorf("Since = %q, want %q", options.Since, "1234")
+	}
+
+	// This is the bug fixed in chunk0-3: the very first subscription must
+	// also set Since to the pre-listing snapshot time, not leave it unset
+	// (which would be indistinguishable from "replay nothing").
+	options = eventsOptions(nil, 0)
+	if options.Since != "0" {
+		t.Errorf("Since on first subscription = %q, want %q", options.Since, "0")
+	}
+}
+
+func TestEventsOptionsPassesThroughFilters(t *testing.T) {
+	filters := map[string][]string{"type": {"container"}}
+	options := eventsOptions(filters, 42)
+	if len(options.Filters) != 1 || options.Filters["type"][0] != "container" {
+		t.Errorf("Filters = %v, want %v", options.Filters, filters)
+	}
+}
+
+func TestIsInterestingExtEvent(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		ev   *docker.APIEvents
+		want bool
+	}{
+		{"start", &docker.APIEvents{Status: "start"}, true},
+		{"die", &docker.APIEvents{Status: "die"}, true},
+		{"network connect", &docker.APIEvents{Type: "network", Action: "connect"}, true},
+		{"network disconnect", &docker.APIEvents{Type: "network", Action: "disconnect"}, true},
+		{"health_status", &docker.APIEvents{Type: "container", Action: "health_status: healthy"}, true},
+		{"pause", &docker.APIEvents{Type: "container", Action: "pause"}, true},
+		{"unpause", &docker.APIEvents{Type: "container", Action: "unpause"}, true},
+		{"rename", &docker.APIEvents{Type: "container", Action: "rename"}, true},
+		{"destroy", &docker.APIEvents{Type: "container", Action: "destroy"}, true},
+		{"uninteresting container action", &docker.APIEvents{Type: "container", Action: "exec_create"}, false},
+		{"uninteresting event type", &docker.APIEvents{Type: "image", Action: "pull"}, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := isInterestingExtEvent(testCase.ev); got != testCase.want {
+				t.Errorf("isInterestingExtEvent(%+v) = %v, want %v", testCase.ev, got, testCase.want)
+			}
+		})
+	}
+}
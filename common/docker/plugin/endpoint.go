@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/weaveworks/weave/common"
+)
+
+// createEndpoint allocates an address for a new endpoint from IPAM and
+// attaches a veth pair to the weave bridge. The container-side end stays in
+// the host namespace until Join moves it into the sandbox.
+func (p *Plugin) createEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req createEndpointRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+
+	ip, err := p.ipam.Allocate(req.EndpointID)
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+
+	hostVeth, containerVeth, err := p.attacher.AttachEndpoint(req.EndpointID, nil)
+	if err != nil {
+		if releaseErr := p.ipam.Release(req.EndpointID); releaseErr != nil {
+			Log.Errorf("[plugin] Error releasing address for endpoint %s: %s", req.EndpointID, releaseErr)
+		}
+		replyError(w, err)
+		return
+	}
+	p.rememberVeths(req.EndpointID, vethPair{hostVeth: hostVeth, containerVeth: containerVeth})
+
+	ones, _ := p.ipam.Subnet().Mask.Size()
+	Log.Infof("[plugin] CreateEndpoint %s -> %s", req.EndpointID, ip)
+	reply(w, createEndpointResponse{
+		Interface: &endpointInterface{
+			Address: fmt.Sprintf("%s/%d", ip, ones),
+		},
+	})
+}
+
+func (p *Plugin) deleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req deleteEndpointRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+	if err := p.attacher.DetachEndpoint(req.EndpointID); err != nil {
+		Log.Errorf("[plugin] Error detaching endpoint %s: %s", req.EndpointID, err)
+	}
+	p.forgetVeths(req.EndpointID)
+	if err := p.ipam.Release(req.EndpointID); err != nil {
+		replyError(w, err)
+		return
+	}
+	Log.Infof("[plugin] DeleteEndpoint %s", req.EndpointID)
+	reply(w, map[string]interface{}{})
+}
+
+func (p *Plugin) endpointInfo(w http.ResponseWriter, r *http.Request) {
+	var req endpointInfoRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+	reply(w, endpointInfoResponse{Value: map[string]interface{}{}})
+}
+
+// join moves the container-side veth created in createEndpoint into the
+// sandbox namespace Docker has prepared for the container.
+func (p *Plugin) join(w http.ResponseWriter, r *http.Request) {
+	var req joinRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+
+	veths, ok := p.lookupVeths(req.EndpointID)
+	if !ok {
+		replyError(w, fmt.Errorf("no veth pair found for endpoint %s - was CreateEndpoint called?", req.EndpointID))
+		return
+	}
+	if err := p.attacher.MoveToNamespace(veths.containerVeth, req.SandboxKey); err != nil {
+		replyError(w, err)
+		return
+	}
+
+	var resp joinResponse
+	resp.InterfaceName.SrcName = veths.containerVeth
+	resp.InterfaceName.DstPrefix = "ethwe"
+	Log.Infof("[plugin] Join %s", req.EndpointID)
+	reply(w, resp)
+}
+
+func (p *Plugin) leave(w http.ResponseWriter, r *http.Request) {
+	var req leaveRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+	Log.Infof("[plugin] Leave %s", req.EndpointID)
+	reply(w, map[string]interface{}{})
+}
@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeIPAM struct {
+	subnet *net.IPNet
+}
+
+func (f *fakeIPAM) Allocate(ident string) (net.IP, error) { return net.ParseIP("10.32.0.2"), nil }
+func (f *fakeIPAM) Release(ident string) error            { return nil }
+func (f *fakeIPAM) Subnet() *net.IPNet                    { return f.subnet }
+
+type fakeAttacher struct {
+	attachCalls int
+	detachCalls int
+}
+
+func (f *fakeAttacher) AttachEndpoint(endpointID string, mac net.HardwareAddr) (string, string, error) {
+	f.attachCalls++
+	return "vethhost" + endpointID, "vethcontainer" + endpointID, nil
+}
+
+func (f *fakeAttacher) DetachEndpoint(endpointID string) error {
+	f.detachCalls++
+	return nil
+}
+
+func (f *fakeAttacher) MoveToNamespace(containerVeth, sandboxKey string) error { return nil }
+
+func newTestPlugin() (*Plugin, *fakeAttacher) {
+	_, subnet, _ := net.ParseCIDR("10.32.0.0/12")
+	attacher := &fakeAttacher{}
+	return NewPlugin(&fakeIPAM{subnet: subnet}, attacher), attacher
+}
+
+func TestJoinReusesCreateEndpointVeths(t *testing.T) {
+	p, attacher := newTestPlugin()
+	const epID = "ep1"
+
+	createBody, _ := json.Marshal(createEndpointRequest{EndpointID: epID})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/NetworkDriver.CreateEndpoint", bytes.NewReader(createBody))
+	p.createEndpoint(w, r)
+	if w.Code != 200 {
+		t.Fatalf("CreateEndpoint: unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if attacher.attachCalls != 1 {
+		t.Fatalf("expected 1 AttachEndpoint call after CreateEndpoint, got %d", attacher.attachCalls)
+	}
+
+	joinBody, _ := json.Marshal(joinRequest{EndpointID: epID, SandboxKey: "/var/run/netns/ep1"})
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/NetworkDriver.Join", bytes.NewReader(joinBody))
+	p.join(w, r)
+	if w.Code != 200 {
+		t.Fatalf("Join: unexpected status %d: %s", w.Code, w.Body.String())
+	}
+
+	if attacher.attachCalls != 1 {
+		t.Fatalf("Join must not call AttachEndpoint again; expected 1 call total, got %d", attacher.attachCalls)
+	}
+
+	var resp joinResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode Join response: %s", err)
+	}
+	if resp.InterfaceName.SrcName != "vethcontainer"+epID {
+		t.Fatalf("expected Join to reuse the veth created by CreateEndpoint, got %q", resp.InterfaceName.SrcName)
+	}
+
+	deleteBody, _ := json.Marshal(deleteEndpointRequest{EndpointID: epID})
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/NetworkDriver.DeleteEndpoint", bytes.NewReader(deleteBody))
+	p.deleteEndpoint(w, r)
+	if attacher.detachCalls != 1 {
+		t.Fatalf("expected 1 DetachEndpoint call after DeleteEndpoint, got %d", attacher.detachCalls)
+	}
+	if _, ok := p.lookupVeths(epID); ok {
+		t.Fatalf("DeleteEndpoint should have forgotten the endpoint's veths")
+	}
+}
+
+func TestJoinWithoutCreateEndpointFails(t *testing.T) {
+	p, _ := newTestPlugin()
+
+	joinBody, _ := json.Marshal(joinRequest{EndpointID: "unknown", SandboxKey: "/var/run/netns/unknown"})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/NetworkDriver.Join", bytes.NewReader(joinBody))
+	p.join(w, r)
+
+	var resp errorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("could not decode Join response: %s", err)
+	}
+	if resp.Err == "" {
+		t.Fatalf("expected Join to report an error for an endpoint with no CreateEndpoint call")
+	}
+}
@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"net/http"
+
+	. "github.com/weaveworks/weave/common"
+)
+
+// Weave has exactly one network, so CreateNetwork and DeleteNetwork are
+// mostly bookkeeping: the underlying bridge and IPAM pool already exist
+// before the plugin starts, and are torn down independently of Docker.
+
+func (p *Plugin) createNetwork(w http.ResponseWriter, r *http.Request) {
+	var req createNetworkRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+	Log.Infof("[plugin] CreateNetwork %s", req.NetworkID)
+	reply(w, map[string]interface{}{})
+}
+
+func (p *Plugin) deleteNetwork(w http.ResponseWriter, r *http.Request) {
+	var req deleteNetworkRequest
+	if err := decode(r, &req); err != nil {
+		replyError(w, err)
+		return
+	}
+	Log.Infof("[plugin] DeleteNetwork %s", req.NetworkID)
+	reply(w, map[string]interface{}{})
+}
@@ -0,0 +1,142 @@
+// Package plugin implements a Docker Network Plugin (API version 2) that lets
+// users attach containers to weave networks with the native `docker network`
+// and `docker run --network` commands, instead of the `weave` script.
+//
+// The plugin speaks the plain HTTP+JSON protocol Docker uses for legacy
+// plugins: each method is a POST to /NetworkDriver.<Name> carrying a JSON
+// request body, dispatched here to the matching handler.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/weaveworks/weave/common"
+)
+
+const (
+	pluginSpecDir  = "/run/docker/plugins"
+	driverName     = "weave"
+	implementsSpec = "NetworkDriver"
+)
+
+// IPAM is the subset of weave's IP address management that the plugin needs
+// in order to hand out and release addresses for endpoints.
+type IPAM interface {
+	Allocate(ident string) (net.IP, error)
+	Release(ident string) error
+	Subnet() *net.IPNet
+}
+
+// NetworkAttacher attaches and detaches endpoints to the weave bridge and
+// moves the container-side veth into the target network namespace.
+type NetworkAttacher interface {
+	AttachEndpoint(endpointID string, mac net.HardwareAddr) (hostVeth, containerVeth string, err error)
+	DetachEndpoint(endpointID string) error
+	MoveToNamespace(containerVeth, sandboxKey string) error
+}
+
+// Plugin implements the Docker Network Plugin API for weave networks.
+type Plugin struct {
+	ipam     IPAM
+	attacher NetworkAttacher
+
+	mux *http.ServeMux
+
+	mu    sync.Mutex
+	veths map[string]vethPair // endpoint ID -> veth pair created for it by CreateEndpoint
+}
+
+type vethPair struct {
+	hostVeth      string
+	containerVeth string
+}
+
+// NewPlugin creates a plugin that allocates addresses from ipam and attaches
+// endpoints to the network via attacher.
+func NewPlugin(ipam IPAM, attacher NetworkAttacher) *Plugin {
+	p := &Plugin{ipam: ipam, attacher: attacher, mux: http.NewServeMux(), veths: map[string]vethPair{}}
+	p.mux.HandleFunc("/Plugin.Activate", p.activate)
+	p.mux.HandleFunc("/NetworkDriver.CreateNetwork", p.createNetwork)
+	p.mux.HandleFunc("/NetworkDriver.DeleteNetwork", p.deleteNetwork)
+	p.mux.HandleFunc("/NetworkDriver.CreateEndpoint", p.createEndpoint)
+	p.mux.HandleFunc("/NetworkDriver.DeleteEndpoint", p.deleteEndpoint)
+	p.mux.HandleFunc("/NetworkDriver.EndpointOperInfo", p.endpointInfo)
+	p.mux.HandleFunc("/NetworkDriver.Join", p.join)
+	p.mux.HandleFunc("/NetworkDriver.Leave", p.leave)
+	return p
+}
+
+// Listen opens the plugin's Unix socket and starts serving requests from
+// Docker. Placing the socket at the well-known path Docker's plugin
+// discovery scans (pluginSpecDir) is what makes the plugin discoverable -
+// no separate spec file is needed - so this should be called before
+// `docker network create -d weave` is used.
+func (p *Plugin) Listen() error {
+	socketPath := filepath.Join(pluginSpecDir, driverName+".sock")
+	if err := os.MkdirAll(pluginSpecDir, 0755); err != nil {
+		return err
+	}
+	os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	Log.Infof("[plugin] Listening on %s", socketPath)
+	go func() {
+		if err := http.Serve(listener, p.mux); err != nil {
+			Log.Errorf("[plugin] Serve failed: %s", err)
+		}
+	}()
+	return nil
+}
+
+// rememberVeths records the veth pair CreateEndpoint created for endpointID,
+// so Join can move the container end into the sandbox without creating a
+// second, orphaned pair.
+func (p *Plugin) rememberVeths(endpointID string, veths vethPair) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.veths[endpointID] = veths
+}
+
+func (p *Plugin) lookupVeths(endpointID string) (vethPair, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	veths, ok := p.veths[endpointID]
+	return veths, ok
+}
+
+func (p *Plugin) forgetVeths(endpointID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.veths, endpointID)
+}
+
+func (p *Plugin) activate(w http.ResponseWriter, r *http.Request) {
+	reply(w, activateResponse{Implements: []string{implementsSpec}})
+}
+
+func reply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1.2+json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		Log.Errorf("[plugin] Error encoding response: %s", err)
+	}
+}
+
+func replyError(w http.ResponseWriter, err error) {
+	Log.Errorf("[plugin] %s", err)
+	reply(w, errorResponse{Err: err.Error()})
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("could not decode request: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package plugin
+
+// Wire types for the Docker Network Plugin API (v2), as documented at
+// https://docs.docker.com/engine/extend/plugins_network/. Docker calls these
+// over HTTP POST to /NetworkDriver.<Method> with a JSON body and expects a
+// JSON body back; errors are reported via the "Err" field rather than the
+// HTTP status.
+
+type activateResponse struct {
+	Implements []string
+}
+
+type errorResponse struct {
+	Err string
+}
+
+type createNetworkRequest struct {
+	NetworkID string
+	Options   map[string]interface{}
+	IPv4Data  []ipamData
+	IPv6Data  []ipamData
+}
+
+type ipamData struct {
+	AddressSpace string
+	Pool         string
+	Gateway      string
+}
+
+type deleteNetworkRequest struct {
+	NetworkID string
+}
+
+type createEndpointRequest struct {
+	NetworkID  string
+	EndpointID string
+	Interface  *endpointInterface
+	Options    map[string]interface{}
+}
+
+type createEndpointResponse struct {
+	Interface *endpointInterface
+}
+
+type endpointInterface struct {
+	Address     string
+	AddressIPv6 string
+	MacAddress  string
+}
+
+type deleteEndpointRequest struct {
+	NetworkID  string
+	EndpointID string
+}
+
+type endpointInfoRequest struct {
+	NetworkID  string
+	EndpointID string
+}
+
+type endpointInfoResponse struct {
+	Value map[string]interface{}
+}
+
+type joinRequest struct {
+	NetworkID  string
+	EndpointID string
+	SandboxKey string
+	Options    map[string]interface{}
+}
+
+type joinResponse struct {
+	InterfaceName struct {
+		SrcName   string
+		DstPrefix string
+	}
+	Gateway               string
+	GatewayIPv6           string
+	StaticRoutes          []staticRoute
+	DisableGatewayService bool
+}
+
+type staticRoute struct {
+	Destination string
+	RouteType   int
+	NextHop     string
+}
+
+type leaveRequest struct {
+	NetworkID  string
+	EndpointID string
+}